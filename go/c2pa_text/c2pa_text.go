@@ -4,24 +4,30 @@ import (
 	"bytes"
 	"encoding/binary"
 	"errors"
+	"io"
 	"strings"
-	"unicode/utf8"
 
 	"golang.org/x/text/unicode/norm"
 )
 
 // Constants
 const (
-	Version    = 1
-	HeaderSize = 13 // 8 (Magic) + 1 (Version) + 4 (Length)
-	ZWNBSP     = '\ufeff'
-	
+	Version         = 1  // inline wrapper: the JUMBF manifest is carried in the wrapper itself
+	VersionDetached = 2  // detached wrapper: the wrapper carries a hash + URI reference instead
+	HeaderSize      = 13 // 8 (Magic) + 1 (Version) + 4 (Length)
+	ZWNBSP          = '\ufeff'
+
 	VSStart    = 0xFE00
 	VSEnd      = 0xFE0F
 	VSSupStart = 0xE0100
 	VSSupEnd   = 0xE01EF
 )
 
+// AssertionTypeTextHashBinding is the claim assertion type name used by a
+// detached manifest's text-hash-binding assertion, which commits to the
+// surrounding prose rather than to an embedded JUMBF box. See BindTextHash.
+const AssertionTypeTextHashBinding = "manifest.text.hashBinding"
+
 var Magic = []byte("C2PATXT\x00")
 
 // Errors
@@ -55,29 +61,34 @@ func vsToByte(r rune) (byte, bool) {
 
 // EncodeWrapper encodes raw bytes into a C2PA Text Manifest Wrapper string.
 func EncodeWrapper(manifestBytes []byte) string {
+	return encodeWrapperFrame(Version, manifestBytes)
+}
+
+// encodeWrapperFrame encodes payload as a ZWNBSP-anchored variation-selector
+// wrapper: Magic, version, a 4-byte big-endian length, then payload itself.
+// EncodeWrapper and EncodeDetachedWrapper only differ in version and payload.
+func encodeWrapperFrame(version byte, payload []byte) string {
 	var buf bytes.Buffer
 	buf.WriteRune(ZWNBSP)
 
-	// Header
 	for _, b := range Magic {
 		r, _ := byteToVS(b)
 		buf.WriteRune(r)
 	}
-	
-	rVersion, _ := byteToVS(byte(Version))
+
+	rVersion, _ := byteToVS(version)
 	buf.WriteRune(rVersion)
 
-	length := uint32(len(manifestBytes))
+	length := uint32(len(payload))
 	lengthBytes := make([]byte, 4)
 	binary.BigEndian.PutUint32(lengthBytes, length)
-	
+
 	for _, b := range lengthBytes {
 		r, _ := byteToVS(b)
 		buf.WriteRune(r)
 	}
 
-	// Body
-	for _, b := range manifestBytes {
+	for _, b := range payload {
 		r, _ := byteToVS(b)
 		buf.WriteRune(r)
 	}
@@ -93,86 +104,147 @@ func EmbedManifest(text string, manifestBytes []byte) string {
 	return normalized + wrapper
 }
 
+// ExtractManifestResult is the return value of ExtractManifestReader.
+type ExtractManifestResult struct {
+	// Manifest holds the decoded wrapper payload, or nil if no wrapper was found.
+	Manifest []byte
+	// Clean is the NFC-normalized text with the wrapper span removed.
+	Clean string
+	// Offset and Length are byte indices/lengths relative to the original
+	// (pre-normalization) stream, or -1 if no wrapper was found.
+	Offset int
+	Length int
+}
+
 // ExtractManifest extracts a C2PA manifest from text.
 // Returns manifest bytes, clean text, offset, length, and error.
 // offset and length are byte indices/lengths relative to the original text.
+//
+// It is built on top of FindAllWrappers and StripWrappers: only inline
+// (Version) wrappers that decoded as WrapperValid are considered, and a
+// second one is an error rather than a silent pick.
 func ExtractManifest(text string) ([]byte, string, int, int, error) {
-	// We need to scan by rune
-	runes := []rune(text)
-	
-	var wrapperStart, wrapperEnd int = -1, -1
-	var decodedBytes []byte
-
-	for i := 0; i < len(runes); i++ {
-		if runes[i] == ZWNBSP {
-			// Potential start
-			startIdx := i
-			var currentBytes []byte
-			j := i + 1
-			
-			for j < len(runes) {
-				b, ok := vsToByte(runes[j])
-				if !ok {
-					break
-				}
-				currentBytes = append(currentBytes, b)
-				j++
-			}
+	spans := FindAllWrappers(text)
+
+	var matches []WrapperSpan
+	for _, span := range spans {
+		if span.Status == WrapperValid && span.Header.Version == Version {
+			matches = append(matches, span)
+		}
+	}
+	if len(matches) > 1 {
+		return nil, "", -1, -1, ErrMultipleWrappers
+	}
+
+	clean := norm.NFC.String(StripWrappers(text, matches))
+	if len(matches) == 0 {
+		return nil, clean, -1, -1, nil
+	}
+
+	span := matches[0]
+	manifest := append([]byte(nil), span.Payload...)
+	return manifest, clean, span.Offset, span.Length, nil
+}
+
+// ExtractManifestReader is the streaming counterpart of ExtractManifest. It
+// consumes r one rune at a time, so the input is never converted to a []rune
+// slice; besides the clean text it returns, only the currently-open candidate
+// wrapper is buffered.
+func ExtractManifestReader(r io.RuneReader) (ExtractManifestResult, error) {
+	manifest, clean, offset, length, err := scanManifest(r.ReadRune, Version)
+	return ExtractManifestResult{Manifest: manifest, Clean: clean, Offset: offset, Length: length}, err
+}
+
+// scanManifest is the shared single-pass scanner behind
+// ExtractManifestReader and ExtractDetachedManifest. next must behave like
+// io.RuneReader.ReadRune, returning io.EOF once the input is exhausted. Only
+// a wrapper whose version byte equals expectedVersion is extracted; any
+// other version is left in place as ordinary text.
+func scanManifest(next func() (rune, int, error), expectedVersion byte) ([]byte, string, int, int, error) {
+	var pendingRune rune
+	var pendingSize int
+	havePending := false
+
+	read := func() (rune, int, error) {
+		if havePending {
+			havePending = false
+			return pendingRune, pendingSize, nil
+		}
+		return next()
+	}
+	pushBack := func(r rune, size int) {
+		pendingRune, pendingSize, havePending = r, size, true
+	}
+
+	var clean strings.Builder
+	var bytePos int
+	var manifest []byte
+	wrapperFound := false
+	offset, length := -1, -1
+
+	for {
+		r, size, err := read()
+		if err != nil {
+			break
+		}
+
+		if r != ZWNBSP {
+			clean.WriteRune(r)
+			bytePos += size
+			continue
+		}
+
+		candidateStart := bytePos
+		var candidate strings.Builder
+		candidate.WriteRune(r)
+		bytePos += size
 
-			// Check header
-			if len(currentBytes) >= HeaderSize {
-				// Check Magic
-				validMagic := true
-				for k := 0; k < 8; k++ {
-					if currentBytes[k] != Magic[k] {
-						validMagic = false
-						break
-					}
-				}
-				
-				if validMagic {
-					if currentBytes[8] == byte(Version) {
-						length := binary.BigEndian.Uint32(currentBytes[9:13])
-						
-						if len(currentBytes) >= HeaderSize+int(length) {
-							if wrapperStart != -1 {
-								return nil, norm.NFC.String(text), -1, -1, ErrMultipleWrappers
-							}
-							
-							wrapperStart = startIdx
-							wrapperEnd = j // j is exclusive end in rune slice
-							
-							decodedBytes = currentBytes[HeaderSize : HeaderSize+int(length)]
-							
-							// Continue searching
-							i = j - 1 // -1 because loop increments
-							continue
-						}
-					}
-				}
+		var payload []byte
+		for {
+			r2, size2, err2 := read()
+			if err2 != nil {
+				break
+			}
+			b, ok := vsToByte(r2)
+			if !ok {
+				pushBack(r2, size2)
+				break
 			}
+			candidate.WriteRune(r2)
+			payload = append(payload, b)
+			bytePos += size2
+		}
+
+		decoded, ok := decodeWrapperPayload(payload, expectedVersion)
+		if !ok {
+			clean.WriteString(candidate.String())
+			continue
 		}
+
+		if wrapperFound {
+			return nil, "", -1, -1, ErrMultipleWrappers
+		}
+		wrapperFound = true
+		offset = candidateStart
+		length = candidate.Len()
+		manifest = append([]byte(nil), decoded...)
 	}
 
-	if wrapperStart != -1 {
-		// Convert rune indices to byte indices
-		preRunes := runes[:wrapperStart]
-		wrapperRunes := runes[wrapperStart:wrapperEnd]
-		
-		startByte := len(string(preRunes))
-		lengthByte := len(string(wrapperRunes))
-
-		// Reconstruct string without wrapper
-		pre := string(runes[:wrapperStart])
-		post := string(runes[wrapperEnd:])
-		clean := norm.NFC.String(pre + post)
-		
-		// Need to copy bytes to avoid reference issues
-		outBytes := make([]byte, len(decodedBytes))
-		copy(outBytes, decodedBytes)
-		
-		return outBytes, clean, startByte, lengthByte, nil
+	if !wrapperFound {
+		return nil, norm.NFC.String(clean.String()), -1, -1, nil
 	}
+	return manifest, norm.NFC.String(clean.String()), offset, length, nil
+}
 
-	return nil, norm.NFC.String(text), -1, -1, nil
+// decodeWrapperPayload validates and slices the decoded header+payload bytes
+// of a candidate wrapper against expectedVersion. ok is false if the
+// candidate is too short, has the wrong magic/version, or declares more
+// payload than it carries. It defers to classifyWrapperPayload so the two
+// scanners agree on exactly what makes a header well-formed.
+func decodeWrapperPayload(payload []byte, expectedVersion byte) (decoded []byte, ok bool) {
+	status, header, decoded := classifyWrapperPayload(payload)
+	if status != WrapperValid || header.Version != expectedVersion {
+		return nil, false
+	}
+	return decoded, true
 }
@@ -0,0 +1,61 @@
+package c2pa_text
+
+import (
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+// prefixOfLength returns filler text of approximately n bytes, containing no
+// ZWNBSP or variation selectors, so scans never open a candidate wrapper.
+func prefixOfLength(n int) string {
+	const unit = "The quick brown fox jumps over the lazy dog. "
+	var b strings.Builder
+	b.Grow(n + len(unit))
+	for b.Len() < n {
+		b.WriteString(unit)
+	}
+	return b.String()
+}
+
+func benchmarkExtractManifestNoWrapper(b *testing.B, size int) {
+	text := prefixOfLength(size)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(text)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _, _, _, err := ExtractManifest(text)
+		if err != nil {
+			b.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+// These scale the non-wrapper prefix by 100x. With no wrapper present,
+// ExtractManifest returns the already-NFC input as its clean text, so
+// allocs/op should stay flat as the prefix grows: there is no O(N) scratch
+// buffer such as the old []rune(text) conversion.
+func BenchmarkExtractManifestNoWrapper1KB(b *testing.B) { benchmarkExtractManifestNoWrapper(b, 1<<10) }
+func BenchmarkExtractManifestNoWrapper100KB(b *testing.B) {
+	benchmarkExtractManifestNoWrapper(b, 100<<10)
+}
+func BenchmarkExtractManifestNoWrapper10MB(b *testing.B) {
+	benchmarkExtractManifestNoWrapper(b, 10<<20)
+}
+
+func BenchmarkExtractManifestWithWrapper(b *testing.B) {
+	manifest := make([]byte, 256)
+	binary.BigEndian.PutUint32(manifest[0:4], 252)
+	copy(manifest[4:8], []byte("jumb"))
+
+	text := prefixOfLength(1<<20) + EncodeWrapper(manifest) + prefixOfLength(1<<20)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(text)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _, _, _, err := ExtractManifest(text)
+		if err != nil {
+			b.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
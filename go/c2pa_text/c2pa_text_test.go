@@ -2,6 +2,7 @@ package c2pa_text
 
 import (
 	"encoding/binary"
+	"strings"
 	"testing"
 
 	"golang.org/x/text/unicode/norm"
@@ -57,3 +58,49 @@ func TestExtractManifestMultipleWrappersErrors(t *testing.T) {
 		t.Fatalf("expected ErrMultipleWrappers, got %v", err)
 	}
 }
+
+func TestExtractManifestReaderMatchesExtractManifest(t *testing.T) {
+	manifest := make([]byte, 8)
+	binary.BigEndian.PutUint32(manifest[0:4], 8)
+	copy(manifest[4:8], []byte("jumb"))
+
+	embedded := EmbedManifest("hello, world", manifest)
+
+	result, err := ExtractManifestReader(strings.NewReader(embedded))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(result.Manifest) != string(manifest) {
+		t.Fatalf("extracted manifest mismatch")
+	}
+
+	wantManifest, wantClean, wantOffset, wantLength, err := ExtractManifest(embedded)
+	if err != nil {
+		t.Fatalf("unexpected error from ExtractManifest: %v", err)
+	}
+	if string(result.Manifest) != string(wantManifest) {
+		t.Fatalf("manifest mismatch vs ExtractManifest")
+	}
+	if result.Clean != wantClean {
+		t.Fatalf("clean text mismatch: got %q expected %q", result.Clean, wantClean)
+	}
+	if result.Offset != wantOffset || result.Length != wantLength {
+		t.Fatalf("offset/length mismatch: got (%d,%d) expected (%d,%d)", result.Offset, result.Length, wantOffset, wantLength)
+	}
+}
+
+func TestExtractManifestReaderNoWrapper(t *testing.T) {
+	result, err := ExtractManifestReader(strings.NewReader("just plain text"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Manifest != nil {
+		t.Fatalf("expected no manifest, got %v", result.Manifest)
+	}
+	if result.Clean != "just plain text" {
+		t.Fatalf("unexpected clean text: %q", result.Clean)
+	}
+	if result.Offset != -1 || result.Length != -1 {
+		t.Fatalf("expected offset/length -1, got (%d,%d)", result.Offset, result.Length)
+	}
+}
@@ -0,0 +1,212 @@
+package c2pa_text
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// This file implements just enough of RFC 8949 (CBOR) to decode and encode
+// the handful of shapes COSE_Sign1 and C2PA claims use: unsigned/negative
+// integers, byte/text strings, arrays, maps, tags, and the simple values
+// false, true, null and undefined. Indefinite-length items and floating point are not supported,
+// since none of the structures this package verifies use them.
+
+// cborTag is a decoded CBOR tag (major type 6): the tag number plus the
+// value it wraps.
+type cborTag struct {
+	Number uint64
+	Value  interface{}
+}
+
+// decodeCBOR decodes a single CBOR data item from the front of b, returning
+// the decoded value and the remaining, unconsumed bytes.
+//
+// Decoded values are represented as: int64 (unsigned and negative integers),
+// []byte (byte strings), string (text strings), []interface{} (arrays),
+// map[interface{}]interface{} (maps), cborTag (tagged values), bool, or nil.
+func decodeCBOR(b []byte) (interface{}, []byte, error) {
+	if len(b) == 0 {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+
+	major := b[0] >> 5
+	info := b[0] & 0x1F
+	arg, rest, err := decodeCBORArgument(info, b[1:])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch major {
+	case 0:
+		return int64(arg), rest, nil
+	case 1:
+		return -1 - int64(arg), rest, nil
+	case 2:
+		if uint64(len(rest)) < arg {
+			return nil, nil, io.ErrUnexpectedEOF
+		}
+		return append([]byte(nil), rest[:arg]...), rest[arg:], nil
+	case 3:
+		if uint64(len(rest)) < arg {
+			return nil, nil, io.ErrUnexpectedEOF
+		}
+		return string(rest[:arg]), rest[arg:], nil
+	case 4:
+		// Each array entry consumes at least 1 byte, so an arg this large
+		// can't possibly be backed by rest; reject before the make(), which
+		// would otherwise panic on an attacker-controlled huge length.
+		if arg > uint64(len(rest)) {
+			return nil, nil, io.ErrUnexpectedEOF
+		}
+		items := make([]interface{}, 0, arg)
+		for i := uint64(0); i < arg; i++ {
+			var v interface{}
+			if v, rest, err = decodeCBOR(rest); err != nil {
+				return nil, nil, err
+			}
+			items = append(items, v)
+		}
+		return items, rest, nil
+	case 5:
+		// Each map entry consumes at least 2 bytes (key + value), so bound
+		// arg against rest before the make() for the same reason as case 4.
+		if arg > uint64(len(rest)) {
+			return nil, nil, io.ErrUnexpectedEOF
+		}
+		m := make(map[interface{}]interface{}, arg)
+		for i := uint64(0); i < arg; i++ {
+			var k, v interface{}
+			if k, rest, err = decodeCBOR(rest); err != nil {
+				return nil, nil, err
+			}
+			if v, rest, err = decodeCBOR(rest); err != nil {
+				return nil, nil, err
+			}
+			m[k] = v
+		}
+		return m, rest, nil
+	case 6:
+		var v interface{}
+		if v, rest, err = decodeCBOR(rest); err != nil {
+			return nil, nil, err
+		}
+		return cborTag{Number: arg, Value: v}, rest, nil
+	case 7:
+		switch info {
+		case 20:
+			return false, rest, nil
+		case 21:
+			return true, rest, nil
+		case 22, 23:
+			return nil, rest, nil
+		default:
+			return nil, nil, fmt.Errorf("cbor: unsupported simple/float value (info=%d)", info)
+		}
+	default:
+		return nil, nil, fmt.Errorf("cbor: unsupported major type %d", major)
+	}
+}
+
+// decodeCBORArgument decodes the argument that follows a major-type byte
+// whose low 5 bits are info, returning the argument and remaining bytes.
+func decodeCBORArgument(info byte, b []byte) (uint64, []byte, error) {
+	switch {
+	case info < 24:
+		return uint64(info), b, nil
+	case info == 24:
+		if len(b) < 1 {
+			return 0, nil, io.ErrUnexpectedEOF
+		}
+		return uint64(b[0]), b[1:], nil
+	case info == 25:
+		if len(b) < 2 {
+			return 0, nil, io.ErrUnexpectedEOF
+		}
+		return uint64(binary.BigEndian.Uint16(b)), b[2:], nil
+	case info == 26:
+		if len(b) < 4 {
+			return 0, nil, io.ErrUnexpectedEOF
+		}
+		return uint64(binary.BigEndian.Uint32(b)), b[4:], nil
+	case info == 27:
+		if len(b) < 8 {
+			return 0, nil, io.ErrUnexpectedEOF
+		}
+		return binary.BigEndian.Uint64(b), b[8:], nil
+	default:
+		return 0, nil, fmt.Errorf("cbor: unsupported additional info %d", info)
+	}
+}
+
+func asInt64(v interface{}) (int64, bool) {
+	i, ok := v.(int64)
+	return i, ok
+}
+
+func asBytes(v interface{}) ([]byte, bool) {
+	b, ok := v.([]byte)
+	return b, ok
+}
+
+func asString(v interface{}) (string, bool) {
+	s, ok := v.(string)
+	return s, ok
+}
+
+func asArray(v interface{}) ([]interface{}, bool) {
+	a, ok := v.([]interface{})
+	return a, ok
+}
+
+func asMap(v interface{}) (map[interface{}]interface{}, bool) {
+	m, ok := v.(map[interface{}]interface{})
+	return m, ok
+}
+
+func mapGetInt(m map[interface{}]interface{}, key int64) (interface{}, bool) {
+	v, ok := m[key]
+	return v, ok
+}
+
+func mapGetString(m map[interface{}]interface{}, key string) (interface{}, bool) {
+	v, ok := m[key]
+	return v, ok
+}
+
+// cborHead encodes a CBOR major-type-and-argument head for n.
+func cborHead(major byte, n uint64) []byte {
+	switch {
+	case n < 24:
+		return []byte{major<<5 | byte(n)}
+	case n <= 0xFF:
+		return []byte{major<<5 | 24, byte(n)}
+	case n <= 0xFFFF:
+		b := make([]byte, 3)
+		b[0] = major<<5 | 25
+		binary.BigEndian.PutUint16(b[1:], uint16(n))
+		return b
+	case n <= 0xFFFFFFFF:
+		b := make([]byte, 5)
+		b[0] = major<<5 | 26
+		binary.BigEndian.PutUint32(b[1:], uint32(n))
+		return b
+	default:
+		b := make([]byte, 9)
+		b[0] = major<<5 | 27
+		binary.BigEndian.PutUint64(b[1:], n)
+		return b
+	}
+}
+
+func encodeByteString(b []byte) []byte {
+	return append(cborHead(2, uint64(len(b))), b...)
+}
+
+func encodeTextString(s string) []byte {
+	return append(cborHead(3, uint64(len(s))), []byte(s)...)
+}
+
+func encodeArrayHeader(n int) []byte {
+	return cborHead(4, uint64(n))
+}
@@ -0,0 +1,193 @@
+package c2pa_text
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Errors returned while decoding or verifying a COSE_Sign1 structure.
+var (
+	ErrCOSEMalformed        = errors.New("cose: malformed Sign1 structure")
+	ErrCOSEUnsupportedAlg   = errors.New("cose: unsupported or unrecognized algorithm")
+	ErrCOSESignatureInvalid = errors.New("cose: signature verification failed")
+)
+
+// COSE common header parameter labels (RFC 9052 Table 2/3).
+const (
+	coseHeaderLabelAlg     = 1
+	coseHeaderLabelX5Chain = 33
+)
+
+// coseAlgorithm describes a COSE signing algorithm this package supports.
+// Hash is the zero value for EdDSA, which signs the message directly rather
+// than a digest of it.
+type coseAlgorithm struct {
+	Name string
+	Hash crypto.Hash
+}
+
+// coseAlgorithmsByLabel maps the COSE "alg" header values (RFC 9053 §2) this
+// package supports to their name and digest algorithm.
+var coseAlgorithmsByLabel = map[int64]coseAlgorithm{
+	-7:  {Name: "ES256", Hash: crypto.SHA256},
+	-35: {Name: "ES384", Hash: crypto.SHA384},
+	-36: {Name: "ES512", Hash: crypto.SHA512},
+	-8:  {Name: "EdDSA"},
+}
+
+// coseSign1 is a decoded COSE_Sign1 structure (RFC 9052 §4.2). Protected is
+// kept in its original encoded form since that, not a re-decoded copy, is
+// what the Sig_structure must be built from.
+type coseSign1 struct {
+	Protected   []byte
+	Unprotected map[interface{}]interface{}
+	Signature   []byte
+}
+
+// decodeCOSESign1 decodes b as a COSE_Sign1 structure, optionally wrapped in
+// the CBOR tag 18 some encoders emit. The Sign1 payload field is ignored:
+// C2PA claim signatures are detached, so the payload is always supplied
+// separately as the claim's own JUMBF box bytes.
+func decodeCOSESign1(b []byte) (*coseSign1, error) {
+	v, _, err := decodeCBOR(b)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCOSEMalformed, err)
+	}
+	if tag, ok := v.(cborTag); ok {
+		v = tag.Value
+	}
+
+	arr, ok := asArray(v)
+	if !ok || len(arr) != 4 {
+		return nil, fmt.Errorf("%w: expected a 4-element COSE_Sign1 array", ErrCOSEMalformed)
+	}
+	protected, ok := asBytes(arr[0])
+	if !ok {
+		return nil, fmt.Errorf("%w: protected header is not a byte string", ErrCOSEMalformed)
+	}
+	unprotected, ok := asMap(arr[1])
+	if !ok {
+		return nil, fmt.Errorf("%w: unprotected header is not a map", ErrCOSEMalformed)
+	}
+	signature, ok := asBytes(arr[3])
+	if !ok {
+		return nil, fmt.Errorf("%w: signature is not a byte string", ErrCOSEMalformed)
+	}
+
+	return &coseSign1{Protected: protected, Unprotected: unprotected, Signature: signature}, nil
+}
+
+// Algorithm reads the signing algorithm out of the protected header's alg
+// label (1).
+func (s *coseSign1) Algorithm() (coseAlgorithm, error) {
+	v, _, err := decodeCBOR(s.Protected)
+	if err != nil {
+		return coseAlgorithm{}, fmt.Errorf("%w: malformed protected header: %v", ErrCOSEMalformed, err)
+	}
+	m, ok := asMap(v)
+	if !ok {
+		return coseAlgorithm{}, fmt.Errorf("%w: protected header is not a map", ErrCOSEMalformed)
+	}
+	raw, ok := mapGetInt(m, coseHeaderLabelAlg)
+	if !ok {
+		return coseAlgorithm{}, fmt.Errorf("%w: protected header has no alg label", ErrCOSEMalformed)
+	}
+	label, ok := asInt64(raw)
+	if !ok {
+		return coseAlgorithm{}, fmt.Errorf("%w: alg label is not an integer", ErrCOSEMalformed)
+	}
+	alg, ok := coseAlgorithmsByLabel[label]
+	if !ok {
+		return coseAlgorithm{}, fmt.Errorf("%w: alg %d", ErrCOSEUnsupportedAlg, label)
+	}
+	return alg, nil
+}
+
+// CertChain reads the signer's certificate chain out of the unprotected
+// header's x5chain label (33, RFC 9360), leaf certificate first.
+func (s *coseSign1) CertChain() ([]*x509.Certificate, error) {
+	raw, ok := mapGetInt(s.Unprotected, coseHeaderLabelX5Chain)
+	if !ok {
+		return nil, fmt.Errorf("%w: unprotected header has no x5chain", ErrCOSEMalformed)
+	}
+
+	var der [][]byte
+	switch v := raw.(type) {
+	case []byte:
+		der = [][]byte{v}
+	case []interface{}:
+		for _, item := range v {
+			b, ok := asBytes(item)
+			if !ok {
+				return nil, fmt.Errorf("%w: x5chain entry is not a byte string", ErrCOSEMalformed)
+			}
+			der = append(der, b)
+		}
+	default:
+		return nil, fmt.Errorf("%w: x5chain is neither a byte string nor an array", ErrCOSEMalformed)
+	}
+
+	certs := make([]*x509.Certificate, 0, len(der))
+	for _, d := range der {
+		cert, err := x509.ParseCertificate(d)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid x5chain certificate: %v", ErrCOSEMalformed, err)
+		}
+		certs = append(certs, cert)
+	}
+	return certs, nil
+}
+
+// encodeSig1Structure builds the COSE Sig_structure (RFC 9052 §4.4) that was
+// signed for a detached COSE_Sign1: ["Signature1", protected, external_aad,
+// payload]. external_aad is always empty for C2PA claim signatures.
+func encodeSig1Structure(protected, payload []byte) []byte {
+	var buf []byte
+	buf = append(buf, encodeArrayHeader(4)...)
+	buf = append(buf, encodeTextString("Signature1")...)
+	buf = append(buf, encodeByteString(protected)...)
+	buf = append(buf, encodeByteString(nil)...)
+	buf = append(buf, encodeByteString(payload)...)
+	return buf
+}
+
+// verifySignature checks sig against message under pub, per alg.
+func verifySignature(pub crypto.PublicKey, alg coseAlgorithm, message, sig []byte) error {
+	switch key := pub.(type) {
+	case *ecdsa.PublicKey:
+		if alg.Hash == 0 {
+			return fmt.Errorf("%w: %s is not an ECDSA algorithm", ErrCOSEUnsupportedAlg, alg.Name)
+		}
+		h := alg.Hash.New()
+		h.Write(message)
+		digest := h.Sum(nil)
+
+		byteLen := (key.Curve.Params().BitSize + 7) / 8
+		if len(sig) != 2*byteLen {
+			return fmt.Errorf("%w: %s signature must be %d bytes, got %d", ErrCOSESignatureInvalid, alg.Name, 2*byteLen, len(sig))
+		}
+		r := new(big.Int).SetBytes(sig[:byteLen])
+		s := new(big.Int).SetBytes(sig[byteLen:])
+		if !ecdsa.Verify(key, digest, r, s) {
+			return ErrCOSESignatureInvalid
+		}
+		return nil
+	case ed25519.PublicKey:
+		if alg.Name != "EdDSA" {
+			return fmt.Errorf("%w: %s is not an Ed25519 algorithm", ErrCOSEUnsupportedAlg, alg.Name)
+		}
+		if !ed25519.Verify(key, message, sig) {
+			return ErrCOSESignatureInvalid
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: unsupported signer public key type %T", ErrCOSEUnsupportedAlg, pub)
+	}
+}
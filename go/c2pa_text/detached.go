@@ -0,0 +1,166 @@
+package c2pa_text
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+// Errors returned while decoding a detached wrapper's payload.
+var ErrDetachedPayloadTruncated = errors.New("detached wrapper payload truncated")
+
+// DetachedManifestResult is the return value of ExtractDetachedManifest.
+type DetachedManifestResult struct {
+	// ManifestHash and HashAlg identify the external manifest by digest
+	// (e.g. a 32-byte SHA-256 sum and "sha256").
+	ManifestHash []byte
+	HashAlg      string
+	// URI locates the external manifest, e.g. an https:// or ipfs:// URL.
+	URI string
+	// Clean is the NFC-normalized text with the wrapper span removed.
+	Clean string
+	// Offset and Length are byte indices/lengths of the wrapper relative to
+	// the original text, or -1 if no detached wrapper was found.
+	Offset int
+	Length int
+}
+
+// EncodeDetachedWrapper encodes a reference to an external C2PA manifest —
+// its digest, the algorithm that produced it, and a URI to fetch it from —
+// into a version-2 C2PA Text Manifest Wrapper string. Unlike EncodeWrapper,
+// the JUMBF manifest itself is not embedded in the text.
+func EncodeDetachedWrapper(manifestHash []byte, hashAlg string, uri string) string {
+	return encodeWrapperFrame(VersionDetached, encodeDetachedPayload(manifestHash, hashAlg, uri))
+}
+
+// encodeDetachedPayload serializes a detached wrapper's payload as a simple
+// TLV: a length-prefixed algorithm name, a length-prefixed hash, and a
+// length-prefixed URI.
+func encodeDetachedPayload(manifestHash []byte, hashAlg string, uri string) []byte {
+	var buf bytes.Buffer
+	buf.WriteByte(byte(len(hashAlg)))
+	buf.WriteString(hashAlg)
+	buf.WriteByte(byte(len(manifestHash)))
+	buf.Write(manifestHash)
+	uriLen := make([]byte, 2)
+	binary.BigEndian.PutUint16(uriLen, uint16(len(uri)))
+	buf.Write(uriLen)
+	buf.WriteString(uri)
+	return buf.Bytes()
+}
+
+// decodeDetachedPayload is the inverse of encodeDetachedPayload.
+func decodeDetachedPayload(payload []byte) (manifestHash []byte, hashAlg string, uri string, err error) {
+	pos := 0
+
+	if len(payload) < pos+1 {
+		return nil, "", "", ErrDetachedPayloadTruncated
+	}
+	algLen := int(payload[pos])
+	pos++
+	if len(payload) < pos+algLen {
+		return nil, "", "", ErrDetachedPayloadTruncated
+	}
+	hashAlg = string(payload[pos : pos+algLen])
+	pos += algLen
+
+	if len(payload) < pos+1 {
+		return nil, "", "", ErrDetachedPayloadTruncated
+	}
+	hashLen := int(payload[pos])
+	pos++
+	if len(payload) < pos+hashLen {
+		return nil, "", "", ErrDetachedPayloadTruncated
+	}
+	manifestHash = append([]byte(nil), payload[pos:pos+hashLen]...)
+	pos += hashLen
+
+	if len(payload) < pos+2 {
+		return nil, "", "", ErrDetachedPayloadTruncated
+	}
+	uriLen := int(binary.BigEndian.Uint16(payload[pos : pos+2]))
+	pos += 2
+	if len(payload) < pos+uriLen {
+		return nil, "", "", ErrDetachedPayloadTruncated
+	}
+	uri = string(payload[pos : pos+uriLen])
+
+	return manifestHash, hashAlg, uri, nil
+}
+
+// ExtractDetachedManifest extracts a version-2 (detached) C2PA wrapper from
+// text, mirroring ExtractManifest's single-pass scan. A version-1 (inline)
+// wrapper present in text is left untouched.
+func ExtractDetachedManifest(text string) (DetachedManifestResult, error) {
+	pos := 0
+	raw, clean, offset, length, err := scanManifest(func() (rune, int, error) {
+		if pos >= len(text) {
+			return 0, 0, io.EOF
+		}
+		r, size := utf8.DecodeRuneInString(text[pos:])
+		pos += size
+		return r, size, nil
+	}, VersionDetached)
+	if err != nil {
+		return DetachedManifestResult{}, err
+	}
+	if raw == nil {
+		return DetachedManifestResult{Clean: clean, Offset: -1, Length: -1}, nil
+	}
+
+	hash, alg, uri, err := decodeDetachedPayload(raw)
+	if err != nil {
+		return DetachedManifestResult{}, fmt.Errorf("detached wrapper: %w", err)
+	}
+	return DetachedManifestResult{
+		ManifestHash: hash,
+		HashAlg:      alg,
+		URI:          uri,
+		Clean:        clean,
+		Offset:       offset,
+		Length:       length,
+	}, nil
+}
+
+// BindTextHash computes a digest, under the named algorithm ("sha256",
+// "sha384", or "sha512"), of text's NFC-normalized form with any C2PA
+// wrapper (inline or detached) excised. A claim can commit to this hash —
+// under the AssertionTypeTextHashBinding assertion type — so that tampering
+// with the surrounding prose is detectable even when the manifest itself is
+// stored separately from the text it describes. Returns nil if text
+// contains more than one wrapper, or alg is not a supported digest.
+func BindTextHash(text string, alg string) []byte {
+	clean, err := cleanTextForHashBinding(text)
+	if err != nil {
+		return nil
+	}
+	digest, err := hashBytes(alg, []byte(clean))
+	if err != nil {
+		return nil
+	}
+	return digest
+}
+
+// cleanTextForHashBinding returns text's NFC-normalized form with whichever
+// kind of wrapper is present (if any) removed.
+func cleanTextForHashBinding(text string) (string, error) {
+	_, clean, offset, _, err := ExtractManifest(text)
+	if err != nil {
+		return "", err
+	}
+	if offset != -1 {
+		if detached, err := ExtractDetachedManifest(clean); err != nil || detached.Offset != -1 {
+			return "", ErrMultipleWrappers
+		}
+		return clean, nil
+	}
+
+	detached, err := ExtractDetachedManifest(text)
+	if err != nil {
+		return "", err
+	}
+	return detached.Clean, nil
+}
@@ -0,0 +1,100 @@
+package c2pa_text
+
+import (
+	"crypto/sha256"
+	"testing"
+)
+
+func TestEncodeExtractDetachedManifestRoundTrip(t *testing.T) {
+	hash := sha256.Sum256([]byte("external manifest bytes"))
+	wrapper := EncodeDetachedWrapper(hash[:], "sha256", "https://example.com/manifest.c2pa")
+
+	embedded := "Hello, world. " + wrapper
+	result, err := ExtractDetachedManifest(embedded)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.HashAlg != "sha256" {
+		t.Fatalf("unexpected alg: %q", result.HashAlg)
+	}
+	if string(result.ManifestHash) != string(hash[:]) {
+		t.Fatalf("manifest hash mismatch")
+	}
+	if result.URI != "https://example.com/manifest.c2pa" {
+		t.Fatalf("unexpected uri: %q", result.URI)
+	}
+	if result.Clean != "Hello, world. " {
+		t.Fatalf("unexpected clean text: %q", result.Clean)
+	}
+}
+
+func TestExtractDetachedManifestIgnoresInlineWrapper(t *testing.T) {
+	inline := EmbedManifest("hello", []byte("jumbfbytes"))
+
+	result, err := ExtractDetachedManifest(inline)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Offset != -1 {
+		t.Fatalf("expected no detached wrapper to be found in an inline-wrapped text")
+	}
+}
+
+func TestBindTextHashExcisesWhicheverWrapperIsPresent(t *testing.T) {
+	plain := "The quick brown fox."
+	plainHash := BindTextHash(plain, "sha256")
+	if plainHash == nil {
+		t.Fatalf("expected a hash for plain text")
+	}
+
+	hash := sha256.Sum256([]byte("external manifest bytes"))
+	withDetached := plain + EncodeDetachedWrapper(hash[:], "sha256", "https://example.com/m.c2pa")
+
+	detachedHash := BindTextHash(withDetached, "sha256")
+	if string(detachedHash) != string(plainHash) {
+		t.Fatalf("expected BindTextHash to exclude the detached wrapper from the digest")
+	}
+}
+
+func TestValidateWrapperBytesDispatchesOnVersion(t *testing.T) {
+	jumbf := make([]byte, 8)
+	copy(jumbf[4:8], []byte("jumb"))
+	inline := EncodeWrapper(jumbf)
+
+	inlineBytes := decodeWrapperBytesForTest(t, inline)
+	result := ValidateWrapperBytes(inlineBytes)
+	if result.Version != Version {
+		t.Fatalf("expected version %d, got %d", Version, result.Version)
+	}
+
+	hash := sha256.Sum256([]byte("x"))
+	detached := EncodeDetachedWrapper(hash[:], "sha256", "https://example.com/m.c2pa")
+	detachedBytes := decodeWrapperBytesForTest(t, detached)
+	result = ValidateWrapperBytes(detachedBytes)
+	if !result.Valid {
+		t.Fatalf("expected a valid detached wrapper, got issues: %v", result.Issues)
+	}
+	if result.Version != VersionDetached {
+		t.Fatalf("expected version %d, got %d", VersionDetached, result.Version)
+	}
+}
+
+// decodeWrapperBytesForTest strips the ZWNBSP/variation-selector encoding a
+// wrapper string uses back down to its raw header+payload bytes, the form
+// ValidateWrapperBytes expects.
+func decodeWrapperBytesForTest(t *testing.T, wrapper string) []byte {
+	t.Helper()
+	runes := []rune(wrapper)
+	if len(runes) == 0 || runes[0] != ZWNBSP {
+		t.Fatalf("expected wrapper to start with ZWNBSP")
+	}
+	var out []byte
+	for _, r := range runes[1:] {
+		b, ok := vsToByte(r)
+		if !ok {
+			break
+		}
+		out = append(out, b)
+	}
+	return out
+}
@@ -0,0 +1,251 @@
+package c2pa_text
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Errors returned by ParseJumbf and the box-tree helpers built on top of it.
+var (
+	ErrJumbfBoxTruncated      = errors.New("jumbf: box truncated")
+	ErrJumbfInvalidBoxSize    = errors.New("jumbf: invalid box size")
+	ErrJumbfInvalidToggles    = errors.New("jumbf: invalid description box toggles")
+	ErrJumbfUnterminatedLabel = errors.New("jumbf: unterminated description label")
+	ErrJumbfClaimNotFound     = errors.New("jumbf: c2pa.claim box not found")
+)
+
+// Description box toggle bits (ISO/IEC 19566-5 JUMBF Description Box).
+const (
+	jumdToggleRequestable  = 1 << 0
+	jumdToggleLabeled      = 1 << 1
+	jumdToggleHasID        = 1 << 2
+	jumdToggleHasSignature = 1 << 3
+	jumdTogglePrivate      = 1 << 4
+	jumdToggleKnownMask    = jumdToggleRequestable | jumdToggleLabeled | jumdToggleHasID | jumdToggleHasSignature | jumdTogglePrivate
+)
+
+// JumbfBox is a single parsed JUMBF box, recursively describing the 'jumb'
+// superbox tree rooted at a C2PA manifest store.
+//
+// UUID, Toggles, Label, ID and Signature are only populated when Type is
+// "jumd" (a description box); Payload holds the raw contents of any other
+// leaf box (e.g. "cbor", "bfdb"); Children holds the nested boxes of a
+// "jumb" superbox.
+type JumbfBox struct {
+	Size      uint64
+	Type      [4]byte
+	UUID      [16]byte
+	Toggles   byte
+	Label     string
+	ID        *uint32
+	Signature []byte
+	Private   []byte
+	Payload   []byte
+	Children  []JumbfBox
+}
+
+// ParseJumbf recursively parses a JUMBF box tree from b, starting at a single
+// top-level box (typically the c2pa manifest store's 'jumb' superbox).
+// Trailing bytes after the top-level box are ignored.
+func ParseJumbf(b []byte) (*JumbfBox, error) {
+	box, _, err := parseJumbfBox(b)
+	if err != nil {
+		return nil, err
+	}
+	return &box, nil
+}
+
+// parseJumbfBox parses a single box starting at b[0] and returns it along
+// with the number of bytes it consumed.
+func parseJumbfBox(b []byte) (JumbfBox, int, error) {
+	if len(b) < 8 {
+		return JumbfBox{}, 0, fmt.Errorf("%w: header needs 8 bytes, have %d", ErrJumbfBoxTruncated, len(b))
+	}
+
+	size := uint64(binary.BigEndian.Uint32(b[0:4]))
+	var headerLen int
+	var effectiveSize uint64
+
+	switch {
+	case size == 1:
+		if len(b) < 16 {
+			return JumbfBox{}, 0, fmt.Errorf("%w: XLBox needs 16 bytes, have %d", ErrJumbfBoxTruncated, len(b))
+		}
+		effectiveSize = binary.BigEndian.Uint64(b[8:16])
+		headerLen = 16
+	case size == 0:
+		effectiveSize = uint64(len(b))
+		headerLen = 8
+	case size < 8:
+		return JumbfBox{}, 0, fmt.Errorf("%w: %d (minimum is 8)", ErrJumbfInvalidBoxSize, size)
+	default:
+		effectiveSize = size
+		headerLen = 8
+	}
+
+	if effectiveSize < uint64(headerLen) {
+		return JumbfBox{}, 0, fmt.Errorf("%w: declares %d bytes, minimum is %d", ErrJumbfBoxTruncated, effectiveSize, headerLen)
+	}
+	if effectiveSize > uint64(len(b)) {
+		return JumbfBox{}, 0, fmt.Errorf("%w: declares %d bytes, have %d", ErrJumbfBoxTruncated, effectiveSize, len(b))
+	}
+
+	var boxType [4]byte
+	copy(boxType[:], b[4:8])
+	box := JumbfBox{Size: effectiveSize, Type: boxType}
+	payload := b[headerLen:effectiveSize]
+
+	switch boxType {
+	case [4]byte(JumbfSuperboxType):
+		children, err := parseJumbfBoxes(payload)
+		if err != nil {
+			return JumbfBox{}, 0, err
+		}
+		box.Children = children
+	case [4]byte(JumbfDescType):
+		if err := parseJumdPayload(&box, payload); err != nil {
+			return JumbfBox{}, 0, err
+		}
+	default:
+		box.Payload = payload
+	}
+
+	return box, int(effectiveSize), nil
+}
+
+// parseJumbfBoxes parses a flat sequence of sibling boxes, such as the
+// contents of a 'jumb' superbox.
+func parseJumbfBoxes(b []byte) ([]JumbfBox, error) {
+	var boxes []JumbfBox
+	pos := 0
+	for pos < len(b) {
+		box, consumed, err := parseJumbfBox(b[pos:])
+		if err != nil {
+			return nil, err
+		}
+		boxes = append(boxes, box)
+		pos += consumed
+	}
+	return boxes, nil
+}
+
+// parseJumdPayload parses a description box's payload (UUID, toggles, and
+// the fields the toggles declare present) into box.
+func parseJumdPayload(box *JumbfBox, payload []byte) error {
+	if len(payload) < 17 {
+		return fmt.Errorf("%w: description box needs 17 bytes, have %d", ErrJumbfBoxTruncated, len(payload))
+	}
+	copy(box.UUID[:], payload[0:16])
+	box.Toggles = payload[16]
+	pos := 17
+
+	if box.Toggles&^jumdToggleKnownMask != 0 {
+		return fmt.Errorf("%w: %08b", ErrJumbfInvalidToggles, box.Toggles)
+	}
+
+	if box.Toggles&jumdToggleLabeled != 0 {
+		end := bytes.IndexByte(payload[pos:], 0)
+		if end == -1 {
+			return fmt.Errorf("%w", ErrJumbfUnterminatedLabel)
+		}
+		box.Label = string(payload[pos : pos+end])
+		pos += end + 1
+	}
+
+	if box.Toggles&jumdToggleHasID != 0 {
+		if len(payload) < pos+4 {
+			return fmt.Errorf("%w: truncated before ID field", ErrJumbfBoxTruncated)
+		}
+		id := binary.BigEndian.Uint32(payload[pos : pos+4])
+		box.ID = &id
+		pos += 4
+	}
+
+	if box.Toggles&jumdToggleHasSignature != 0 {
+		if len(payload) < pos+32 {
+			return fmt.Errorf("%w: truncated before signature field", ErrJumbfBoxTruncated)
+		}
+		box.Signature = payload[pos : pos+32]
+		pos += 32
+	}
+
+	if box.Toggles&jumdTogglePrivate != 0 {
+		box.Private = payload[pos:]
+	}
+
+	return nil
+}
+
+// description returns box's own description box (its first 'jumd' child),
+// or nil if it has none.
+func description(box JumbfBox) *JumbfBox {
+	for i := range box.Children {
+		if box.Children[i].Type == [4]byte(JumbfDescType) {
+			return &box.Children[i]
+		}
+	}
+	return nil
+}
+
+// findChildByLabel returns the first child of box whose description box is
+// labeled with label, or nil if none matches.
+func findChildByLabel(box *JumbfBox, label string) *JumbfBox {
+	if box == nil {
+		return nil
+	}
+	for i := range box.Children {
+		if d := description(box.Children[i]); d != nil && d.Label == label {
+			return &box.Children[i]
+		}
+	}
+	return nil
+}
+
+// FindManifestStore searches root and its descendants for the 'jumb'
+// superbox describing a C2PA manifest store (identified by
+// C2PAManifestStoreUUID), returning nil if none is found.
+func FindManifestStore(root *JumbfBox) *JumbfBox {
+	if root == nil {
+		return nil
+	}
+	if root.Type == [4]byte(JumbfSuperboxType) {
+		if d := description(*root); d != nil && bytesEqual(d.UUID[:], C2PAManifestStoreUUID) {
+			return root
+		}
+	}
+	for i := range root.Children {
+		if found := FindManifestStore(&root.Children[i]); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// Assertions returns the individual assertion boxes nested under manifest's
+// "c2pa.assertions" box, or nil if manifest has no assertions store. The
+// assertions store's own description box is not included.
+func Assertions(manifest *JumbfBox) []JumbfBox {
+	store := findChildByLabel(manifest, "c2pa.assertions")
+	if store == nil {
+		return nil
+	}
+	var assertions []JumbfBox
+	for _, child := range store.Children {
+		if child.Type == [4]byte(JumbfSuperboxType) {
+			assertions = append(assertions, child)
+		}
+	}
+	return assertions
+}
+
+// Claim returns manifest's "c2pa.claim" box, or ErrJumbfClaimNotFound if it
+// has none.
+func Claim(manifest *JumbfBox) (*JumbfBox, error) {
+	claim := findChildByLabel(manifest, "c2pa.claim")
+	if claim == nil {
+		return nil, ErrJumbfClaimNotFound
+	}
+	return claim, nil
+}
@@ -0,0 +1,112 @@
+package c2pa_text
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// box builds a raw JUMBF box: 4-byte size, 4-byte type, then payload.
+func box(boxType string, payload []byte) []byte {
+	b := make([]byte, 8+len(payload))
+	binary.BigEndian.PutUint32(b[0:4], uint32(len(b)))
+	copy(b[4:8], boxType)
+	copy(b[8:], payload)
+	return b
+}
+
+// jumd builds a description box payload for the given UUID and label.
+func jumd(uuid []byte, label string) []byte {
+	payload := make([]byte, 0, 16+1+len(label)+1)
+	payload = append(payload, uuid...)
+	payload = append(payload, jumdToggleLabeled)
+	payload = append(payload, []byte(label)...)
+	payload = append(payload, 0)
+	return payload
+}
+
+func TestParseJumbfWalksManifestStore(t *testing.T) {
+	claimDesc := box("jumd", jumd(C2PAManifestStoreUUID, "c2pa.claim"))
+	claimBox := box("jumb", claimDesc)
+
+	assertionDesc := box("jumd", jumd(C2PAManifestStoreUUID, "c2pa.assertions.dataHash"))
+	assertion := box("jumb", assertionDesc)
+
+	assertionsStoreDesc := box("jumd", jumd(C2PAManifestStoreUUID, "c2pa.assertions"))
+	assertionsStore := box("jumb", append(assertionsStoreDesc, assertion...))
+
+	manifestDesc := box("jumd", jumd(C2PAManifestStoreUUID, "c2pa.manifest"))
+	manifest := box("jumb", concatBoxes(manifestDesc, claimBox, assertionsStore))
+
+	storeDesc := box("jumd", jumd(C2PAManifestStoreUUID, "c2pa"))
+	store := box("jumb", append(storeDesc, manifest...))
+
+	root, err := ParseJumbf(store)
+	if err != nil {
+		t.Fatalf("ParseJumbf failed: %v", err)
+	}
+
+	found := FindManifestStore(root)
+	if found == nil {
+		t.Fatalf("FindManifestStore did not find the manifest store")
+	}
+
+	manifestBox := found.Children[1]
+	claim, err := Claim(&manifestBox)
+	if err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+	if label := description(*claim).Label; label != "c2pa.claim" {
+		t.Fatalf("unexpected claim label: %q", label)
+	}
+
+	assertions := Assertions(&manifestBox)
+	if len(assertions) != 1 {
+		t.Fatalf("expected 1 assertion, got %d", len(assertions))
+	}
+	if label := description(assertions[0]).Label; label != "c2pa.assertions.dataHash" {
+		t.Fatalf("unexpected assertion label: %q", label)
+	}
+}
+
+func TestParseJumbfRejectsUnknownToggleBits(t *testing.T) {
+	payload := append([]byte{}, C2PAManifestStoreUUID...)
+	payload = append(payload, 0xFF) // no bits in jumdToggleKnownMask cover all of 0xFF
+	desc := box("jumd", payload)
+	root := box("jumb", desc)
+
+	if _, err := ParseJumbf(root); err == nil {
+		t.Fatalf("expected an error for unknown toggle bits")
+	}
+}
+
+func TestParseJumbfRejectsShortXLBoxDeclaredSize(t *testing.T) {
+	// size=1 (XLBox marker), type "jumb", then an 8-byte XLBox size field
+	// declaring 5 bytes total — less than the 16-byte XLBox header itself.
+	b := make([]byte, 20)
+	binary.BigEndian.PutUint32(b[0:4], 1)
+	copy(b[4:8], "jumb")
+	binary.BigEndian.PutUint64(b[8:16], 5)
+
+	if _, err := ParseJumbf(b); err == nil {
+		t.Fatalf("expected an error for a short XLBox declared size")
+	}
+}
+
+func TestParseJumbfRejectsZeroXLBoxDeclaredSize(t *testing.T) {
+	b := make([]byte, 20)
+	binary.BigEndian.PutUint32(b[0:4], 1)
+	copy(b[4:8], "jumb")
+	binary.BigEndian.PutUint64(b[8:16], 0)
+
+	if _, err := ParseJumbf(b); err == nil {
+		t.Fatalf("expected an error for a zero XLBox declared size")
+	}
+}
+
+func concatBoxes(boxes ...[]byte) []byte {
+	var out []byte
+	for _, b := range boxes {
+		out = append(out, b...)
+	}
+	return out
+}
@@ -2,14 +2,15 @@ package c2pa_text
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
 )
 
 // JUMBF Constants (ISO/IEC 19566-5)
 var (
-	JumbfSuperboxType      = []byte("jumb")
-	JumbfDescType          = []byte("jumd")
-	C2PAManifestStoreUUID  = []byte{
+	JumbfSuperboxType     = []byte("jumb")
+	JumbfDescType         = []byte("jumd")
+	C2PAManifestStoreUUID = []byte{
 		0x63, 0x32, 0x70, 0x61, 0x00, 0x11, 0x00, 0x10,
 		0x80, 0x00, 0x00, 0xAA, 0x00, 0x38, 0x9B, 0x71,
 	}
@@ -38,6 +39,18 @@ const (
 	ValidationCodeMissingDescriptionBox ValidationCode = "manifest.jumbf.missingDescriptionBox"
 	ValidationCodeInvalidC2paUuid       ValidationCode = "manifest.jumbf.invalidC2paUuid"
 	ValidationCodeTruncatedJumbf        ValidationCode = "manifest.jumbf.truncated"
+
+	// Full-tree parse failures (ParseJumbf), surfaced via the strict path of
+	// ValidateJumbfStructure.
+	ValidationCodeInvalidToggles     ValidationCode = "manifest.jumbf.invalidToggles"
+	ValidationCodeUnterminatedLabel  ValidationCode = "manifest.jumbf.unterminatedLabel"
+	ValidationCodeUnknownRequiredBox ValidationCode = "manifest.jumbf.unknownRequiredBox"
+
+	// Claim signature failures, surfaced by VerifyManifest.
+	ValidationCodeSignatureInvalid        ValidationCode = "manifest.signature.invalid"
+	ValidationCodeSignatureUntrustedChain ValidationCode = "manifest.signature.untrustedChain"
+	ValidationCodeAssertionHashMismatch   ValidationCode = "manifest.assertion.hashMismatch"
+	ValidationCodeTextHashMismatch        ValidationCode = "manifest.text.hashMismatch"
 )
 
 // ValidationIssue represents a single validation issue.
@@ -190,44 +203,60 @@ func ValidateJumbfStructure(jumbfBytes []byte, strict bool) *ValidationResult {
 	}
 
 	if strict {
-		// Check for description box (jumd)
-		if len(jumbfBytes) < headerSize+8 {
+		box, err := ParseJumbf(jumbfBytes)
+		if err != nil {
+			code, message := classifyJumbfParseError(err)
+			result.AddIssue(code, message, 0, "")
+			return result
+		}
+
+		if d := description(*box); d == nil {
 			result.AddIssue(
 				ValidationCodeMissingDescriptionBox,
-				"JUMBF superbox too short to contain description box",
+				"JUMBF superbox has no description box",
 				headerSize, "",
 			)
 			return result
-		}
-
-		descType := jumbfBytes[headerSize+4 : headerSize+8]
-		if !bytesEqual(descType, JumbfDescType) {
+		} else if !bytesEqual(d.UUID[:], C2PAManifestStoreUUID) {
 			result.AddIssue(
-				ValidationCodeMissingDescriptionBox,
-				fmt.Sprintf("Expected description box 'jumd', got '%s'", string(descType)),
-				headerSize+4, "",
+				ValidationCodeInvalidC2paUuid,
+				"Invalid C2PA manifest store UUID",
+				headerSize+8,
+				fmt.Sprintf("expected=%x, found=%x", C2PAManifestStoreUUID, d.UUID),
 			)
 			return result
 		}
 
-		// Check for C2PA UUID
-		uuidOffset := headerSize + 8
-		if len(jumbfBytes) >= uuidOffset+16 {
-			foundUuid := jumbfBytes[uuidOffset : uuidOffset+16]
-			if !bytesEqual(foundUuid, C2PAManifestStoreUUID) {
-				result.AddIssue(
-					ValidationCodeInvalidC2paUuid,
-					"Invalid C2PA manifest store UUID",
-					uuidOffset,
-					fmt.Sprintf("expected=%x, found=%x", C2PAManifestStoreUUID, foundUuid),
-				)
-			}
+		if _, err := Claim(firstSuperboxChild(box)); err != nil {
+			result.AddIssue(
+				ValidationCodeUnknownRequiredBox,
+				"JUMBF manifest store's manifest is missing its required c2pa.claim box",
+				headerSize, "",
+			)
+			return result
 		}
 	}
 
 	return result
 }
 
+// classifyJumbfParseError maps a ParseJumbf error to the ValidationCode that
+// best describes it.
+func classifyJumbfParseError(err error) (ValidationCode, string) {
+	switch {
+	case errors.Is(err, ErrJumbfInvalidToggles):
+		return ValidationCodeInvalidToggles, err.Error()
+	case errors.Is(err, ErrJumbfUnterminatedLabel):
+		return ValidationCodeUnterminatedLabel, err.Error()
+	case errors.Is(err, ErrJumbfInvalidBoxSize):
+		return ValidationCodeInvalidJumbfBoxSize, err.Error()
+	case errors.Is(err, ErrJumbfBoxTruncated):
+		return ValidationCodeTruncatedJumbf, err.Error()
+	default:
+		return ValidationCodeInvalidJumbfHeader, err.Error()
+	}
+}
+
 // ValidateManifest validates a C2PA manifest before embedding.
 func ValidateManifest(manifestBytes []byte, validateJumbf bool, strict bool) *ValidationResult {
 	result := NewValidationResult()
@@ -278,10 +307,10 @@ func ValidateWrapperBytes(wrapperBytes []byte) *ValidationResult {
 	// Check version
 	version := int(wrapperBytes[8])
 	result.Version = version
-	if version != Version {
+	if version != Version && version != VersionDetached {
 		result.AddIssue(
 			ValidationCodeUnsupportedVersion,
-			fmt.Sprintf("Unsupported version: %d, expected %d", version, Version),
+			fmt.Sprintf("Unsupported version: %d, expected %d or %d", version, Version, VersionDetached),
 			8, "",
 		)
 		return result
@@ -291,27 +320,37 @@ func ValidateWrapperBytes(wrapperBytes []byte) *ValidationResult {
 	declaredLength := binary.BigEndian.Uint32(wrapperBytes[9:13])
 	result.DeclaredLength = declaredLength
 
-	actualJumbfLength := len(wrapperBytes) - HeaderSize
-	result.ActualLength = actualJumbfLength
+	actualPayloadLength := len(wrapperBytes) - HeaderSize
+	result.ActualLength = actualPayloadLength
 
-	if int(declaredLength) != actualJumbfLength {
+	if int(declaredLength) != actualPayloadLength {
 		result.AddIssue(
 			ValidationCodeLengthMismatch,
-			fmt.Sprintf("Length mismatch: declares %d bytes, actual %d", declaredLength, actualJumbfLength),
+			fmt.Sprintf("Length mismatch: declares %d bytes, actual %d", declaredLength, actualPayloadLength),
 			9, "",
 		)
 		return result
 	}
 
-	// Validate JUMBF
-	jumbfBytes := wrapperBytes[HeaderSize:]
-	result.JumbfBytes = jumbfBytes
-	result.ManifestBytes = jumbfBytes
+	payload := wrapperBytes[HeaderSize:]
+	result.ManifestBytes = payload
 
-	jumbfResult := ValidateJumbfStructure(jumbfBytes, false)
-	if !jumbfResult.Valid {
-		result.Issues = append(result.Issues, jumbfResult.Issues...)
-		result.Valid = false
+	switch version {
+	case VersionDetached:
+		if _, _, _, err := decodeDetachedPayload(payload); err != nil {
+			result.AddIssue(
+				ValidationCodeCorruptedWrapper,
+				fmt.Sprintf("Invalid detached wrapper payload: %v", err),
+				HeaderSize, "",
+			)
+		}
+	default: // Version
+		result.JumbfBytes = payload
+		jumbfResult := ValidateJumbfStructure(payload, false)
+		if !jumbfResult.Valid {
+			result.Issues = append(result.Issues, jumbfResult.Issues...)
+			result.Valid = false
+		}
 	}
 
 	return result
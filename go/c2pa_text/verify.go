@@ -0,0 +1,305 @@
+package c2pa_text
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// VerifyOptions controls certificate chain validation in VerifyManifest. The
+// zero value validates against the system time with no extra intermediates.
+type VerifyOptions struct {
+	// Intermediates, if set, supplies additional intermediate certificates
+	// beyond those carried in the COSE_Sign1 x5chain.
+	Intermediates *x509.CertPool
+	// CurrentTime is the time the signer's certificate chain is validated
+	// against. The zero value means time.Now().
+	CurrentTime time.Time
+	// Text, if set, is the surrounding prose a detached manifest's claim may
+	// commit to via an AssertionTypeTextHashBinding assertion. When the
+	// claim has such an assertion but Text is empty, it cannot be verified
+	// and is reported as a mismatch.
+	Text string
+}
+
+// AssertionHashResult reports whether a single assertion referenced by the
+// claim still hashes to the value the claim committed to.
+type AssertionHashResult struct {
+	Label   string
+	Alg     string
+	Matched bool
+}
+
+// VerifyResult is the result of VerifyManifest. It embeds ValidationResult
+// so signature and assertion failures are reported as ValidationIssues the
+// same way the rest of this package reports structural failures.
+type VerifyResult struct {
+	ValidationResult
+
+	SignerSubject    string
+	Chain            []*x509.Certificate
+	Algorithm        string
+	SignedClaim      []byte
+	AssertionResults []AssertionHashResult
+}
+
+// VerifyManifest parses manifest as a JUMBF C2PA manifest store, verifies its
+// claim signature (a COSE_Sign1 structure in the c2pa.signature box) against
+// roots, and recomputes each assertion's hash from the claim's assertions
+// list. A non-nil error indicates manifest could not be processed at all;
+// signature and hash failures are reported in the returned result's Issues
+// instead, following the pattern of this package's other Validate* functions.
+func VerifyManifest(manifest []byte, roots *x509.CertPool, opts *VerifyOptions) (*VerifyResult, error) {
+	if opts == nil {
+		opts = &VerifyOptions{}
+	}
+	result := &VerifyResult{ValidationResult: *NewValidationResult()}
+
+	root, err := ParseJumbf(manifest)
+	if err != nil {
+		code, message := classifyJumbfParseError(err)
+		result.AddIssue(code, message, 0, "")
+		return result, nil
+	}
+
+	store := FindManifestStore(root)
+	if store == nil {
+		result.AddIssue(ValidationCodeInvalidC2paUuid, "no JUMBF box with the C2PA manifest store UUID was found", 0, "")
+		return result, nil
+	}
+	manifestBox := firstSuperboxChild(store)
+	if manifestBox == nil {
+		result.AddIssue(ValidationCodeUnknownRequiredBox, "manifest store has no manifest box", 0, "")
+		return result, nil
+	}
+
+	claimBox, err := Claim(manifestBox)
+	if err != nil {
+		result.AddIssue(ValidationCodeUnknownRequiredBox, err.Error(), 0, "")
+		return result, nil
+	}
+	claimBytes := boxContentPayload(*claimBox)
+
+	sigBox := findChildByLabel(manifestBox, "c2pa.signature")
+	if sigBox == nil {
+		result.AddIssue(ValidationCodeUnknownRequiredBox, "manifest is missing its c2pa.signature box", 0, "")
+		return result, nil
+	}
+
+	sign1, err := decodeCOSESign1(boxContentPayload(*sigBox))
+	if err != nil {
+		result.AddIssue(ValidationCodeSignatureInvalid, err.Error(), 0, "")
+		return result, nil
+	}
+	alg, err := sign1.Algorithm()
+	if err != nil {
+		result.AddIssue(ValidationCodeSignatureInvalid, err.Error(), 0, "")
+		return result, nil
+	}
+	certs, err := sign1.CertChain()
+	if err != nil || len(certs) == 0 {
+		if err == nil {
+			err = errors.New("x5chain contains no certificates")
+		}
+		result.AddIssue(ValidationCodeSignatureInvalid, err.Error(), 0, "")
+		return result, nil
+	}
+	signer := certs[0]
+
+	sigStructure := encodeSig1Structure(sign1.Protected, claimBytes)
+	if err := verifySignature(signer.PublicKey, alg, sigStructure, sign1.Signature); err != nil {
+		result.AddIssue(ValidationCodeSignatureInvalid, err.Error(), 0, "")
+		return result, nil
+	}
+
+	if _, err := verifyCertChain(certs, roots, opts); err != nil {
+		result.AddIssue(ValidationCodeSignatureUntrustedChain, err.Error(), 0, "")
+		return result, nil
+	}
+
+	result.SignerSubject = signer.Subject.String()
+	result.Chain = certs
+	result.Algorithm = alg.Name
+	result.SignedClaim = claimBytes
+
+	refs, err := decodeClaimAssertions(claimBytes)
+	if err != nil {
+		result.AddIssue(ValidationCodeSignatureInvalid, fmt.Sprintf("malformed claim assertions: %v", err), 0, "")
+		return result, nil
+	}
+
+	assertionsByLabel := make(map[string]JumbfBox)
+	for _, assertion := range Assertions(manifestBox) {
+		if d := description(assertion); d != nil {
+			assertionsByLabel[d.Label] = assertion
+		}
+	}
+
+	assertionMismatch := false
+	textHashMismatch := false
+	for _, ref := range refs {
+		matched := false
+
+		if ref.Label == AssertionTypeTextHashBinding {
+			if opts.Text != "" {
+				if digest := BindTextHash(opts.Text, ref.Alg); digest != nil {
+					matched = bytesEqual(digest, ref.Hash)
+				}
+			}
+			if !matched {
+				textHashMismatch = true
+			}
+		} else {
+			if assertion, found := assertionsByLabel[ref.Label]; found {
+				if digest, err := hashBytes(ref.Alg, boxContentPayload(assertion)); err == nil {
+					matched = bytesEqual(digest, ref.Hash)
+				}
+			}
+			if !matched {
+				assertionMismatch = true
+			}
+		}
+
+		result.AssertionResults = append(result.AssertionResults, AssertionHashResult{
+			Label:   ref.Label,
+			Alg:     ref.Alg,
+			Matched: matched,
+		})
+	}
+	if assertionMismatch {
+		result.AddIssue(ValidationCodeAssertionHashMismatch, "one or more assertions do not match the hash the claim committed to", 0, "")
+	}
+	if textHashMismatch {
+		result.AddIssue(ValidationCodeTextHashMismatch, "the surrounding text does not match the hash the claim committed to", 0, "")
+	}
+
+	return result, nil
+}
+
+// verifyCertChain validates certs[0] (the signer) up to roots, treating
+// certs[1:] and opts.Intermediates as available intermediates.
+func verifyCertChain(certs []*x509.Certificate, roots *x509.CertPool, opts *VerifyOptions) ([][]*x509.Certificate, error) {
+	intermediates := x509.NewCertPool()
+	if opts.Intermediates != nil {
+		intermediates = opts.Intermediates.Clone()
+	}
+	for _, c := range certs[1:] {
+		intermediates.AddCert(c)
+	}
+
+	currentTime := opts.CurrentTime
+	if currentTime.IsZero() {
+		currentTime = time.Now()
+	}
+
+	return certs[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   currentTime,
+	})
+}
+
+// claimAssertionRef is one entry of a claim's "assertions" array: a
+// hashed reference to an assertion box by its JUMBF label.
+type claimAssertionRef struct {
+	Label string
+	Alg   string
+	Hash  []byte
+}
+
+// decodeClaimAssertions reads the "assertions" field out of a CBOR-encoded
+// c2pa.claim box.
+func decodeClaimAssertions(claimBytes []byte) ([]claimAssertionRef, error) {
+	v, _, err := decodeCBOR(claimBytes)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := asMap(v)
+	if !ok {
+		return nil, errors.New("claim is not a CBOR map")
+	}
+	rawAssertions, ok := mapGetString(m, "assertions")
+	if !ok {
+		return nil, errors.New("claim has no assertions field")
+	}
+	arr, ok := asArray(rawAssertions)
+	if !ok {
+		return nil, errors.New("claim assertions field is not an array")
+	}
+
+	refs := make([]claimAssertionRef, 0, len(arr))
+	for _, item := range arr {
+		entry, ok := asMap(item)
+		if !ok {
+			return nil, errors.New("claim assertion reference is not a map")
+		}
+		urlVal, _ := mapGetString(entry, "url")
+		url, _ := asString(urlVal)
+		hashVal, _ := mapGetString(entry, "hash")
+		hash, _ := asBytes(hashVal)
+		algVal, _ := mapGetString(entry, "alg")
+		alg, _ := asString(algVal)
+
+		refs = append(refs, claimAssertionRef{
+			Label: jumbfLabelFromURL(url),
+			Alg:   alg,
+			Hash:  hash,
+		})
+	}
+	return refs, nil
+}
+
+// jumbfLabelFromURL extracts the final path segment of a claim assertion
+// reference URL (e.g. "self#jumbf=c2pa.assertions/c2pa.hash.data" ->
+// "c2pa.hash.data").
+func jumbfLabelFromURL(url string) string {
+	if idx := strings.LastIndex(url, "/"); idx != -1 {
+		return url[idx+1:]
+	}
+	return url
+}
+
+// hashBytes computes the digest of payload under the named algorithm
+// ("sha256", "sha384", or "sha512", case-insensitive).
+func hashBytes(alg string, payload []byte) ([]byte, error) {
+	switch strings.ToLower(alg) {
+	case "sha256":
+		sum := sha256.Sum256(payload)
+		return sum[:], nil
+	case "sha384":
+		sum := sha512.Sum384(payload)
+		return sum[:], nil
+	case "sha512":
+		sum := sha512.Sum512(payload)
+		return sum[:], nil
+	default:
+		return nil, fmt.Errorf("unsupported assertion hash algorithm %q", alg)
+	}
+}
+
+// firstSuperboxChild returns box's first 'jumb' child, i.e. the active
+// manifest nested directly under a manifest store.
+func firstSuperboxChild(box *JumbfBox) *JumbfBox {
+	for i := range box.Children {
+		if box.Children[i].Type == [4]byte(JumbfSuperboxType) {
+			return &box.Children[i]
+		}
+	}
+	return nil
+}
+
+// boxContentPayload returns the raw payload of box's first non-description
+// child, i.e. the actual content (CBOR claim, CBOR assertion, ...) nested
+// inside a 'jumb' superbox alongside its 'jumd' description box.
+func boxContentPayload(box JumbfBox) []byte {
+	for _, child := range box.Children {
+		if child.Type != [4]byte(JumbfDescType) {
+			return child.Payload
+		}
+	}
+	return box.Payload
+}
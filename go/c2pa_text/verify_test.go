@@ -0,0 +1,319 @@
+package c2pa_text
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// buildTestManifest assembles a minimal but structurally real C2PA manifest
+// store: a manifest containing one assertion, a claim referencing it by
+// hash, and an ES256 COSE_Sign1 claim signature from a self-signed root.
+func buildTestManifest(t *testing.T) (store []byte, root *x509.Certificate, signerKey *ecdsa.PrivateKey) {
+	t.Helper()
+
+	rootKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate root key: %v", err)
+	}
+	rootTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test root"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	rootDER, err := x509.CreateCertificate(rand.Reader, rootTmpl, rootTmpl, &rootKey.PublicKey, rootKey)
+	if err != nil {
+		t.Fatalf("create root cert: %v", err)
+	}
+	rootCert, err := x509.ParseCertificate(rootDER)
+	if err != nil {
+		t.Fatalf("parse root cert: %v", err)
+	}
+
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate leaf key: %v", err)
+	}
+	leafTmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "test signer"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, leafTmpl, rootTmpl, &leafKey.PublicKey, rootKey)
+	if err != nil {
+		t.Fatalf("create leaf cert: %v", err)
+	}
+
+	assertionPayload := []byte("assertion-content")
+	assertion := box("jumb", concatBoxes(
+		box("jumd", jumd(C2PAManifestStoreUUID, "c2pa.hash.data")),
+		box("cbor", assertionPayload),
+	))
+	assertionsStore := box("jumb", concatBoxes(
+		box("jumd", jumd(C2PAManifestStoreUUID, "c2pa.assertions")),
+		assertion,
+	))
+
+	sum := sha256.Sum256(assertionPayload)
+	var claimCBOR []byte
+	claimCBOR = append(claimCBOR, 0xA1) // map(1): {"assertions": [...]}
+	claimCBOR = append(claimCBOR, encodeTextString("assertions")...)
+	claimCBOR = append(claimCBOR, encodeArrayHeader(1)...)
+	claimCBOR = append(claimCBOR, 0xA3) // map(3): {"url":.., "hash":.., "alg":..}
+	claimCBOR = append(claimCBOR, encodeTextString("url")...)
+	claimCBOR = append(claimCBOR, encodeTextString("self#jumbf=c2pa.assertions/c2pa.hash.data")...)
+	claimCBOR = append(claimCBOR, encodeTextString("hash")...)
+	claimCBOR = append(claimCBOR, encodeByteString(sum[:])...)
+	claimCBOR = append(claimCBOR, encodeTextString("alg")...)
+	claimCBOR = append(claimCBOR, encodeTextString("sha256")...)
+
+	claimBox := box("jumb", concatBoxes(
+		box("jumd", jumd(C2PAManifestStoreUUID, "c2pa.claim")),
+		box("cbor", claimCBOR),
+	))
+
+	protected := []byte{0xA1, 0x01, 0x26} // {1: -7} i.e. alg: ES256
+	digest := sha256.Sum256(encodeSig1Structure(protected, claimCBOR))
+	r, s, err := ecdsa.Sign(rand.Reader, leafKey, digest[:])
+	if err != nil {
+		t.Fatalf("sign claim: %v", err)
+	}
+	rawSig := make([]byte, 64)
+	r.FillBytes(rawSig[:32])
+	s.FillBytes(rawSig[32:])
+
+	var unprotected []byte
+	unprotected = append(unprotected, 0xA1) // map(1): {33: [leafDER]}
+	unprotected = append(unprotected, cborHead(0, coseHeaderLabelX5Chain)...)
+	unprotected = append(unprotected, encodeArrayHeader(1)...)
+	unprotected = append(unprotected, encodeByteString(leafDER)...)
+
+	var sign1 []byte
+	sign1 = append(sign1, encodeArrayHeader(4)...)
+	sign1 = append(sign1, encodeByteString(protected)...)
+	sign1 = append(sign1, unprotected...)
+	sign1 = append(sign1, encodeByteString(nil)...) // payload: detached
+	sign1 = append(sign1, encodeByteString(rawSig)...)
+
+	sigBox := box("jumb", concatBoxes(
+		box("jumd", jumd(C2PAManifestStoreUUID, "c2pa.signature")),
+		box("cbor", sign1),
+	))
+
+	manifest := box("jumb", concatBoxes(
+		box("jumd", jumd(C2PAManifestStoreUUID, "c2pa.manifest")),
+		claimBox, assertionsStore, sigBox,
+	))
+	store = box("jumb", concatBoxes(box("jumd", jumd(C2PAManifestStoreUUID, "c2pa")), manifest))
+
+	return store, rootCert, leafKey
+}
+
+func TestVerifyManifestValidatesSignatureAndAssertionHashes(t *testing.T) {
+	manifest, rootCert, _ := buildTestManifest(t)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(rootCert)
+
+	result, err := VerifyManifest(manifest, roots, nil)
+	if err != nil {
+		t.Fatalf("VerifyManifest returned an error: %v", err)
+	}
+	if !result.Valid {
+		t.Fatalf("expected a valid result, got issues: %v", result.Issues)
+	}
+	if result.Algorithm != "ES256" {
+		t.Fatalf("unexpected algorithm: %q", result.Algorithm)
+	}
+	if result.SignerSubject != "CN=test signer" {
+		t.Fatalf("unexpected signer subject: %q", result.SignerSubject)
+	}
+	if len(result.AssertionResults) != 1 || !result.AssertionResults[0].Matched {
+		t.Fatalf("expected one matching assertion, got %+v", result.AssertionResults)
+	}
+}
+
+// buildTestManifestWithTextBinding is a trimmed-down variant of
+// buildTestManifest whose claim commits to a text-hash-binding assertion
+// (see BindTextHash) instead of an embedded JUMBF assertion box.
+func buildTestManifestWithTextBinding(t *testing.T, text string) (store []byte, root *x509.Certificate) {
+	t.Helper()
+
+	rootKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate root key: %v", err)
+	}
+	rootTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test root"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	rootDER, err := x509.CreateCertificate(rand.Reader, rootTmpl, rootTmpl, &rootKey.PublicKey, rootKey)
+	if err != nil {
+		t.Fatalf("create root cert: %v", err)
+	}
+	rootCert, err := x509.ParseCertificate(rootDER)
+	if err != nil {
+		t.Fatalf("parse root cert: %v", err)
+	}
+
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate leaf key: %v", err)
+	}
+	leafTmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "test signer"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, leafTmpl, rootTmpl, &leafKey.PublicKey, rootKey)
+	if err != nil {
+		t.Fatalf("create leaf cert: %v", err)
+	}
+
+	textHash := BindTextHash(text, "sha256")
+
+	var claimCBOR []byte
+	claimCBOR = append(claimCBOR, 0xA1) // map(1): {"assertions": [...]}
+	claimCBOR = append(claimCBOR, encodeTextString("assertions")...)
+	claimCBOR = append(claimCBOR, encodeArrayHeader(1)...)
+	claimCBOR = append(claimCBOR, 0xA3) // map(3): {"url":.., "hash":.., "alg":..}
+	claimCBOR = append(claimCBOR, encodeTextString("url")...)
+	claimCBOR = append(claimCBOR, encodeTextString("self#jumbf=c2pa.assertions/"+AssertionTypeTextHashBinding)...)
+	claimCBOR = append(claimCBOR, encodeTextString("hash")...)
+	claimCBOR = append(claimCBOR, encodeByteString(textHash)...)
+	claimCBOR = append(claimCBOR, encodeTextString("alg")...)
+	claimCBOR = append(claimCBOR, encodeTextString("sha256")...)
+
+	claimBox := box("jumb", concatBoxes(
+		box("jumd", jumd(C2PAManifestStoreUUID, "c2pa.claim")),
+		box("cbor", claimCBOR),
+	))
+
+	protected := []byte{0xA1, 0x01, 0x26} // {1: -7} i.e. alg: ES256
+	digest := sha256.Sum256(encodeSig1Structure(protected, claimCBOR))
+	r, s, err := ecdsa.Sign(rand.Reader, leafKey, digest[:])
+	if err != nil {
+		t.Fatalf("sign claim: %v", err)
+	}
+	rawSig := make([]byte, 64)
+	r.FillBytes(rawSig[:32])
+	s.FillBytes(rawSig[32:])
+
+	var unprotected []byte
+	unprotected = append(unprotected, 0xA1) // map(1): {33: [leafDER]}
+	unprotected = append(unprotected, cborHead(0, coseHeaderLabelX5Chain)...)
+	unprotected = append(unprotected, encodeArrayHeader(1)...)
+	unprotected = append(unprotected, encodeByteString(leafDER)...)
+
+	var sign1 []byte
+	sign1 = append(sign1, encodeArrayHeader(4)...)
+	sign1 = append(sign1, encodeByteString(protected)...)
+	sign1 = append(sign1, unprotected...)
+	sign1 = append(sign1, encodeByteString(nil)...) // payload: detached
+	sign1 = append(sign1, encodeByteString(rawSig)...)
+
+	sigBox := box("jumb", concatBoxes(
+		box("jumd", jumd(C2PAManifestStoreUUID, "c2pa.signature")),
+		box("cbor", sign1),
+	))
+
+	manifest := box("jumb", concatBoxes(
+		box("jumd", jumd(C2PAManifestStoreUUID, "c2pa.manifest")),
+		claimBox, sigBox,
+	))
+	store = box("jumb", concatBoxes(box("jumd", jumd(C2PAManifestStoreUUID, "c2pa")), manifest))
+
+	return store, rootCert
+}
+
+func TestVerifyManifestChecksTextHashBinding(t *testing.T) {
+	text := "The quick brown fox jumps over the lazy dog."
+	manifest, rootCert := buildTestManifestWithTextBinding(t, text)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(rootCert)
+
+	result, err := VerifyManifest(manifest, roots, &VerifyOptions{Text: text})
+	if err != nil {
+		t.Fatalf("VerifyManifest returned an error: %v", err)
+	}
+	if !result.Valid {
+		t.Fatalf("expected a valid result, got issues: %v", result.Issues)
+	}
+	if len(result.AssertionResults) != 1 || !result.AssertionResults[0].Matched {
+		t.Fatalf("expected a matching text-hash-binding assertion, got %+v", result.AssertionResults)
+	}
+
+	result, err = VerifyManifest(manifest, roots, &VerifyOptions{Text: "tampered text"})
+	if err != nil {
+		t.Fatalf("VerifyManifest returned an error: %v", err)
+	}
+	if code := result.PrimaryCode(); code != ValidationCodeTextHashMismatch {
+		t.Fatalf("expected %q for tampered text, got %q", ValidationCodeTextHashMismatch, code)
+	}
+}
+
+func TestVerifyManifestRejectsUntrustedChain(t *testing.T) {
+	manifest, _, _ := buildTestManifest(t)
+
+	result, err := VerifyManifest(manifest, x509.NewCertPool(), nil)
+	if err != nil {
+		t.Fatalf("VerifyManifest returned an error: %v", err)
+	}
+	if result.Valid {
+		t.Fatalf("expected an untrusted-chain failure")
+	}
+	if code := result.PrimaryCode(); code != ValidationCodeSignatureUntrustedChain {
+		t.Fatalf("expected %q, got %q", ValidationCodeSignatureUntrustedChain, code)
+	}
+}
+
+func TestVerifyManifestRejectsMalformedJumbfWithoutPanicking(t *testing.T) {
+	shortXLBox := make([]byte, 20)
+	binary.BigEndian.PutUint32(shortXLBox[0:4], 1)
+	copy(shortXLBox[4:8], "jumb")
+	binary.BigEndian.PutUint64(shortXLBox[8:16], 5)
+
+	zeroXLBox := make([]byte, 20)
+	binary.BigEndian.PutUint32(zeroXLBox[0:4], 1)
+	copy(zeroXLBox[4:8], "jumb")
+	binary.BigEndian.PutUint64(zeroXLBox[8:16], 0)
+
+	truncated := []byte("jumb")[:3]
+
+	for name, manifest := range map[string][]byte{
+		"short XLBox declared size": shortXLBox,
+		"zero XLBox declared size":  zeroXLBox,
+		"truncated box header":      truncated,
+	} {
+		t.Run(name, func(t *testing.T) {
+			result, err := VerifyManifest(manifest, x509.NewCertPool(), nil)
+			if err != nil {
+				t.Fatalf("VerifyManifest returned an error: %v", err)
+			}
+			if result.Valid {
+				t.Fatalf("expected a validation failure for malformed JUMBF")
+			}
+		})
+	}
+}
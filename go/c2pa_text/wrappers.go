@@ -0,0 +1,197 @@
+package c2pa_text
+
+import (
+	"encoding/binary"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// WrapperStatus describes the outcome of decoding a ZWNBSP-anchored
+// candidate region found by FindAllWrappers.
+type WrapperStatus string
+
+const (
+	// WrapperValid means the candidate decoded to a complete, recognized
+	// header and its declared payload was fully present.
+	WrapperValid WrapperStatus = "valid"
+	// WrapperInvalidMagic means the candidate's first 8 decoded bytes did
+	// not match Magic.
+	WrapperInvalidMagic WrapperStatus = "invalidMagic"
+	// WrapperUnsupportedVersion means the magic matched but the version
+	// byte was neither Version nor VersionDetached.
+	WrapperUnsupportedVersion WrapperStatus = "unsupportedVersion"
+	// WrapperTruncated means the candidate ended before a full header, or
+	// the header's declared length, could be read.
+	WrapperTruncated WrapperStatus = "truncated"
+	// WrapperNotVSSequence means the ZWNBSP was followed by no variation
+	// selectors at all, so it is very likely an ordinary ZWNBSP in prose
+	// rather than the start of a wrapper.
+	WrapperNotVSSequence WrapperStatus = "notVSSequence"
+)
+
+// WrapperHeader is a candidate wrapper's decoded header fields.
+type WrapperHeader struct {
+	Version byte
+	Length  uint32
+}
+
+// WrapperSpan describes a single ZWNBSP-anchored candidate region found by
+// FindAllWrappers. Offset and Length are byte indices/lengths relative to
+// the text FindAllWrappers was called with. Header and Payload are only
+// populated when Status is WrapperValid; Payload is the header's declared
+// payload, already sliced to its declared Length.
+type WrapperSpan struct {
+	Offset  int
+	Length  int
+	Status  WrapperStatus
+	Header  *WrapperHeader
+	Payload []byte
+}
+
+// FindAllWrappers scans text for every ZWNBSP-anchored candidate wrapper
+// region, decoding as much of each as it can. Unlike ExtractManifest it
+// never fails on multiple wrappers or skips corrupted ones: every candidate
+// is reported, whatever its version or decode status, so callers can de-dup,
+// migrate, or repair a document with StripWrappers and ReplaceWrapper.
+func FindAllWrappers(text string) []WrapperSpan {
+	pos := 0
+	return scanAllWrapperSpans(func() (rune, int, error) {
+		if pos >= len(text) {
+			return 0, 0, io.EOF
+		}
+		r, size := utf8.DecodeRuneInString(text[pos:])
+		pos += size
+		return r, size, nil
+	})
+}
+
+// scanAllWrapperSpans is the single-pass engine behind FindAllWrappers. next
+// must behave like io.RuneReader.ReadRune, returning io.EOF once the input
+// is exhausted.
+func scanAllWrapperSpans(next func() (rune, int, error)) []WrapperSpan {
+	var pendingRune rune
+	var pendingSize int
+	havePending := false
+
+	read := func() (rune, int, error) {
+		if havePending {
+			havePending = false
+			return pendingRune, pendingSize, nil
+		}
+		return next()
+	}
+	pushBack := func(r rune, size int) {
+		pendingRune, pendingSize, havePending = r, size, true
+	}
+
+	var spans []WrapperSpan
+	var bytePos int
+
+	for {
+		r, size, err := read()
+		if err != nil {
+			break
+		}
+		if r != ZWNBSP {
+			bytePos += size
+			continue
+		}
+
+		candidateStart := bytePos
+		candidateLen := size
+		bytePos += size
+
+		var payload []byte
+		for {
+			r2, size2, err2 := read()
+			if err2 != nil {
+				break
+			}
+			b, ok := vsToByte(r2)
+			if !ok {
+				pushBack(r2, size2)
+				break
+			}
+			payload = append(payload, b)
+			candidateLen += size2
+			bytePos += size2
+		}
+
+		status, header, decoded := classifyWrapperPayload(payload)
+		spans = append(spans, WrapperSpan{
+			Offset:  candidateStart,
+			Length:  candidateLen,
+			Status:  status,
+			Header:  header,
+			Payload: decoded,
+		})
+	}
+
+	return spans
+}
+
+// classifyWrapperPayload decodes the VS-decoded bytes following a ZWNBSP
+// into a status and, if WrapperValid, the header and its declared payload.
+func classifyWrapperPayload(payload []byte) (WrapperStatus, *WrapperHeader, []byte) {
+	if len(payload) == 0 {
+		return WrapperNotVSSequence, nil, nil
+	}
+	if len(payload) < 8 {
+		return WrapperTruncated, nil, nil
+	}
+	if !bytesEqual(payload[0:8], Magic) {
+		return WrapperInvalidMagic, nil, nil
+	}
+	if len(payload) < HeaderSize {
+		return WrapperTruncated, nil, nil
+	}
+	version := payload[8]
+	if version != Version && version != VersionDetached {
+		return WrapperUnsupportedVersion, nil, nil
+	}
+	declaredLength := binary.BigEndian.Uint32(payload[9:13])
+	if len(payload) < HeaderSize+int(declaredLength) {
+		return WrapperTruncated, nil, nil
+	}
+	header := &WrapperHeader{Version: version, Length: declaredLength}
+	return WrapperValid, header, payload[HeaderSize : HeaderSize+int(declaredLength)]
+}
+
+// StripWrappers returns text with every span in spans removed. Spans need
+// not be in order, but must not overlap; a span nested inside an
+// already-removed span is skipped rather than double-counted.
+func StripWrappers(text string, spans []WrapperSpan) string {
+	if len(spans) == 0 {
+		return text
+	}
+	sorted := append([]WrapperSpan(nil), spans...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Offset < sorted[j].Offset })
+
+	var buf strings.Builder
+	pos := 0
+	for _, span := range sorted {
+		if span.Offset < pos {
+			continue
+		}
+		buf.WriteString(text[pos:span.Offset])
+		pos = span.Offset + span.Length
+	}
+	buf.WriteString(text[pos:])
+	return buf.String()
+}
+
+// ReplaceWrapper returns text with span replaced by a freshly encoded
+// wrapper carrying newManifest as its payload. The replacement reuses
+// span.Header's version when present, so a repair keeps a detached wrapper
+// detached; callers migrating versions can set span.Header.Version (or
+// supply a synthetic WrapperSpan) before calling ReplaceWrapper.
+func ReplaceWrapper(text string, span WrapperSpan, newManifest []byte) string {
+	version := byte(Version)
+	if span.Header != nil {
+		version = span.Header.Version
+	}
+	replacement := encodeWrapperFrame(version, newManifest)
+	return text[:span.Offset] + replacement + text[span.Offset+span.Length:]
+}
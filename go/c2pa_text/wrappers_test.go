@@ -0,0 +1,129 @@
+package c2pa_text
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestFindAllWrappersClassifiesEachCandidate(t *testing.T) {
+	manifest := make([]byte, 8)
+	binary.BigEndian.PutUint32(manifest[0:4], 8)
+	copy(manifest[4:8], []byte("jumb"))
+
+	valid := EncodeWrapper(manifest)
+	bareZWNBSP := string(rune(ZWNBSP)) + "no variation selectors here"
+
+	text := "hello " + valid + " world " + bareZWNBSP
+
+	spans := FindAllWrappers(text)
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 candidates, got %d", len(spans))
+	}
+
+	if spans[0].Status != WrapperValid {
+		t.Fatalf("expected first candidate to be valid, got %v", spans[0].Status)
+	}
+	if spans[0].Header == nil || spans[0].Header.Version != Version {
+		t.Fatalf("expected first candidate's header version to be %d, got %+v", Version, spans[0].Header)
+	}
+	if string(spans[0].Payload) != string(manifest) {
+		t.Fatalf("payload mismatch: got %x want %x", spans[0].Payload, manifest)
+	}
+
+	if spans[1].Status != WrapperNotVSSequence {
+		t.Fatalf("expected second candidate to be notVSSequence, got %v", spans[1].Status)
+	}
+	if spans[1].Header != nil {
+		t.Fatalf("expected no header for a non-wrapper ZWNBSP")
+	}
+}
+
+func TestFindAllWrappersReportsInvalidMagicAndTruncated(t *testing.T) {
+	badMagic := encodeWrapperFrame(Version, nil)
+	badMagic = string([]rune(badMagic)[:1]) + string([]rune(badMagic)[2:]) // drop one magic byte, shifting everything
+
+	spans := FindAllWrappers(badMagic)
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 candidate, got %d", len(spans))
+	}
+	if spans[0].Status != WrapperInvalidMagic {
+		t.Fatalf("expected invalidMagic, got %v", spans[0].Status)
+	}
+
+	truncated := string([]rune(EncodeWrapper(nil))[:9]) // ZWNBSP + magic only, no version/length
+	spans = FindAllWrappers(truncated)
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 candidate, got %d", len(spans))
+	}
+	if spans[0].Status != WrapperTruncated {
+		t.Fatalf("expected truncated, got %v", spans[0].Status)
+	}
+}
+
+func TestFindAllWrappersReportsUnsupportedVersion(t *testing.T) {
+	wrapper := encodeWrapperFrame(99, []byte("payload"))
+	spans := FindAllWrappers(wrapper)
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 candidate, got %d", len(spans))
+	}
+	if spans[0].Status != WrapperUnsupportedVersion {
+		t.Fatalf("expected unsupportedVersion, got %v", spans[0].Status)
+	}
+}
+
+func TestStripWrappersRemovesOnlySelectedSpans(t *testing.T) {
+	manifest := []byte("m")
+	first := EncodeWrapper(manifest)
+	second := EncodeWrapper(manifest)
+	text := first + "keep me" + second
+
+	spans := FindAllWrappers(text)
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 candidates, got %d", len(spans))
+	}
+
+	stripped := StripWrappers(text, spans[:1])
+	if stripped != "keep me"+second {
+		t.Fatalf("unexpected result: %q", stripped)
+	}
+}
+
+func TestReplaceWrapperRepairsCorruptedPayload(t *testing.T) {
+	good := make([]byte, 8)
+	binary.BigEndian.PutUint32(good[0:4], 8)
+	copy(good[4:8], []byte("jumb"))
+
+	corrupt := encodeWrapperFrame(Version, []byte("short"))
+	text := "before " + corrupt + " after"
+
+	spans := FindAllWrappers(text)
+	if len(spans) != 1 || spans[0].Status != WrapperValid {
+		t.Fatalf("expected a single valid (but garbage-payload) span, got %+v", spans)
+	}
+
+	repaired := ReplaceWrapper(text, spans[0], good)
+	manifest, clean, _, _, err := ExtractManifest(repaired)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(manifest) != string(good) {
+		t.Fatalf("manifest mismatch after repair: got %x want %x", manifest, good)
+	}
+	if clean != "before  after" {
+		t.Fatalf("unexpected clean text: %q", clean)
+	}
+}
+
+func TestExtractManifestStillErrorsOnMultipleWrappers(t *testing.T) {
+	manifest := make([]byte, 8)
+	binary.BigEndian.PutUint32(manifest[0:4], 8)
+	copy(manifest[4:8], []byte("jumb"))
+
+	base := EmbedManifest("hello", manifest)
+	double := base + EncodeWrapper(manifest)
+
+	_, _, _, _, err := ExtractManifest(double)
+	if err != ErrMultipleWrappers {
+		t.Fatalf("expected ErrMultipleWrappers, got %v", err)
+	}
+}